@@ -0,0 +1,93 @@
+package btrfs
+
+import (
+	"strconv"
+
+	"github.com/control-center/go-fsutils/btrfs/native"
+)
+
+// Backend reads FileSystem info for a given path, either by shelling out
+// to btrfs-progs or by decoding the on-disk format directly.
+type Backend interface {
+	FileSystem(path string) (*FileSystem, error)
+}
+
+// ExecBackend shells out to the `btrfs` CLI, as GetFileSystem has always
+// done. It requires btrfs-progs to be installed and the filesystem to be
+// mounted.
+var ExecBackend Backend = execBackend{}
+
+// NativeBackend reads the btrfs on-disk format directly via the native
+// package, so it works against unmounted devices, read-only images, and
+// hosts without btrfs-progs installed.
+var NativeBackend Backend = nativeBackend{}
+
+// DefaultBackend is the Backend used by the package-level GetFileSystem.
+// It defaults to ExecBackend for backwards compatibility; callers that want
+// the native reader can either set this to NativeBackend or call
+// NativeBackend.FileSystem directly.
+var DefaultBackend = ExecBackend
+
+type execBackend struct{}
+
+func (execBackend) FileSystem(path string) (*FileSystem, error) {
+	return execFileSystem(path)
+}
+
+type nativeBackend struct{}
+
+func (nativeBackend) FileSystem(path string) (*FileSystem, error) {
+	nfs, err := native.OpenPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// fromNative's Subvolumes keep a live native.Subvolume pointing back
+	// into nfs (for Walk), so nfs must stay open for the lifetime of the
+	// returned FileSystem rather than being closed here; callers are
+	// responsible for calling FileSystem.Close when they're done.
+	fs := fromNative(nfs)
+	fs.native = nfs
+	return fs, nil
+}
+
+// fromNative adapts a native.FileSystem (populated by directly parsing the
+// on-disk format) into the package's FileSystem type, so callers can use
+// either Backend interchangeably.
+func fromNative(nfs *native.FileSystem) *FileSystem {
+	fs := &FileSystem{
+		UUID:         nfs.UUID,
+		TotalDevices: uint64(len(nfs.Devices)),
+		UsedBytes:    nfs.UsedBytes,
+	}
+
+	for _, d := range nfs.Devices {
+		fs.devices = append(fs.devices, Device{
+			DevID: formatDevID(d.DevID),
+			Size:  d.Size,
+			Used:  d.Used,
+		})
+	}
+
+	for _, df := range nfs.DFData {
+		fs.dfData = append(fs.dfData, DFData{
+			DataType: df.DataType,
+			Level:    "single",
+			Total:    df.Total,
+		})
+	}
+
+	for _, sv := range nfs.Subvolumes {
+		fs.subvolumes = append(fs.subvolumes, Subvolume{
+			ID:     formatDevID(sv.TreeID),
+			Gen:    uint32(sv.Generation),
+			native: sv,
+		})
+	}
+
+	return fs
+}
+
+func formatDevID(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
@@ -0,0 +1,16 @@
+package btrfs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDumpTarRequiresNativeSubvolume(t *testing.T) {
+	sv := &Subvolume{Path: "/mnt/whatever"}
+
+	var buf bytes.Buffer
+	err := sv.DumpTar(&buf)
+	if err == nil {
+		t.Fatal("expected an error for a Subvolume without a native reader")
+	}
+}
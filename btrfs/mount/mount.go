@@ -0,0 +1,204 @@
+// Package mount exposes a native.FileSystem (or one of its subvolumes)
+// through a read-only FUSE mount, giving callers a normal POSIX view of a
+// btrfs image even when the kernel can't mount it itself — point this
+// package at the raw device or image, pick a subvolume by TreeID, and
+// browse it like any other directory.
+package mount
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"bazil.org/fuse"
+	fusefs "bazil.org/fuse/fs"
+
+	"github.com/control-center/go-fsutils/btrfs/native"
+)
+
+// MountOptions configures MountRO.
+type MountOptions struct {
+	// NoChecksums disables verifying FS-tree node checksums while
+	// indexing the subvolume, so a caller can still browse a filesystem
+	// with known node corruption instead of MountRO failing outright at
+	// the first bad node. It does not cover file data: this reader
+	// doesn't implement the checksum tree, so extent contents are never
+	// verified either way.
+	NoChecksums bool
+}
+
+// MountRO mounts the subvolume identified by treeID (see
+// native.FileSystem.Subvolumes) from fs at mountpoint, read-only, and
+// blocks serving FUSE requests until ctx is canceled or an unrecoverable
+// error occurs.
+func MountRO(ctx context.Context, fs *native.FileSystem, treeID uint64, mountpoint string, opts MountOptions) error {
+	sv, err := findSubvolume(fs, treeID)
+	if err != nil {
+		return err
+	}
+
+	index, err := buildIndex(sv, opts)
+	if err != nil {
+		return fmt.Errorf("mount: indexing subvolume %d: %w", treeID, err)
+	}
+
+	c, err := fuse.Mount(
+		mountpoint,
+		fuse.ReadOnly(),
+		fuse.FSName("btrfs"),
+		fuse.Subtype("go-fsutils-native"),
+	)
+	if err != nil {
+		return fmt.Errorf("mount: %w", err)
+	}
+	defer c.Close()
+
+	filesys := &fileSystem{sv: sv, index: index}
+
+	serveErrCh := make(chan error, 1)
+	go func() { serveErrCh <- fusefs.Serve(c, filesys) }()
+
+	select {
+	case <-ctx.Done():
+		if err := fuse.Unmount(mountpoint); err != nil {
+			return fmt.Errorf("mount: unmounting %v: %w", mountpoint, err)
+		}
+		<-serveErrCh
+		return ctx.Err()
+	case err := <-serveErrCh:
+		return err
+	}
+}
+
+func findSubvolume(fs *native.FileSystem, treeID uint64) (*native.Subvolume, error) {
+	for _, sv := range fs.Subvolumes {
+		if sv.TreeID == treeID {
+			return sv, nil
+		}
+	}
+	return nil, fmt.Errorf("mount: no subvolume with tree id %d", treeID)
+}
+
+// entry is one file or directory discovered by walking the subvolume.
+type entry struct {
+	inode    native.Inode
+	path     string
+	children []string
+}
+
+// buildIndex walks the whole subvolume up front so Lookup/ReadDir can be
+// answered from memory; this trades a slower mount for O(1) FUSE calls,
+// reasonable for the forensics/recovery use case this package targets.
+func buildIndex(sv *native.Subvolume, opts MountOptions) (map[string]*entry, error) {
+	index := map[string]*entry{}
+	walkOpts := native.WalkOptions{SkipChecksums: opts.NoChecksums}
+	err := sv.WalkOpts(walkOpts, func(p string, inode native.Inode) error {
+		index[p] = &entry{inode: inode, path: p}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for p := range index {
+		if p == "/" {
+			continue
+		}
+		parent := path.Dir(p)
+		if parentEntry, ok := index[parent]; ok {
+			parentEntry.children = append(parentEntry.children, p)
+		}
+	}
+	return index, nil
+}
+
+const (
+	sIFMT  = 0170000
+	sIFDIR = 0040000
+	sIFLNK = 0120000
+)
+
+type fileSystem struct {
+	sv    *native.Subvolume
+	index map[string]*entry
+}
+
+func (f *fileSystem) Root() (fusefs.Node, error) {
+	root, ok := f.index["/"]
+	if !ok {
+		return nil, fmt.Errorf("mount: subvolume has no root directory entry")
+	}
+	return &node{fs: f, entry: root}, nil
+}
+
+// node implements the bazil.org/fuse/fs interfaces for both files and
+// directories; which operations apply depends on the inode's file type.
+type node struct {
+	fs    *fileSystem
+	entry *entry
+}
+
+func (n *node) Attr(ctx context.Context, a *fuse.Attr) error {
+	a.Inode = n.entry.inode.Number
+	a.Size = n.entry.inode.Size
+	a.Mode = os.FileMode(n.entry.inode.Mode &^ sIFMT)
+	if n.entry.inode.Mode&sIFMT == sIFDIR {
+		a.Mode |= os.ModeDir
+	}
+	if n.entry.inode.Mode&sIFMT == sIFLNK {
+		a.Mode |= os.ModeSymlink
+	}
+	a.Uid = n.entry.inode.UID
+	a.Gid = n.entry.inode.GID
+	a.Nlink = n.entry.inode.Nlink
+	a.Mtime = n.entry.inode.Mtime
+	a.Valid = time.Minute
+	return nil
+}
+
+func (n *node) Lookup(ctx context.Context, name string) (fusefs.Node, error) {
+	childPath := path.Join(n.entry.path, name)
+	child, ok := n.fs.index[childPath]
+	if !ok {
+		return nil, fuse.ENOENT
+	}
+	return &node{fs: n.fs, entry: child}, nil
+}
+
+func (n *node) ReadDirAll(ctx context.Context) ([]fuse.Dirent, error) {
+	var dirents []fuse.Dirent
+	for _, childPath := range n.entry.children {
+		child := n.fs.index[childPath]
+		dirents = append(dirents, fuse.Dirent{
+			Inode: child.inode.Number,
+			Name:  path.Base(childPath),
+			Type:  directoryType(child.inode),
+		})
+	}
+	return dirents, nil
+}
+
+func directoryType(inode native.Inode) fuse.DirentType {
+	switch inode.Mode & sIFMT {
+	case sIFDIR:
+		return fuse.DT_Dir
+	case sIFLNK:
+		return fuse.DT_Link
+	default:
+		return fuse.DT_File
+	}
+}
+
+func (n *node) ReadAll(ctx context.Context) ([]byte, error) {
+	return n.fs.sv.ReadFile(n.entry.inode)
+}
+
+func (n *node) Readlink(ctx context.Context, req *fuse.ReadlinkRequest) (string, error) {
+	data, err := n.fs.sv.ReadFile(n.entry.inode)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
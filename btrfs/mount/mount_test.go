@@ -0,0 +1,102 @@
+package mount
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+
+	"github.com/control-center/go-fsutils/btrfs/native"
+)
+
+func TestFindSubvolume(t *testing.T) {
+	fs := &native.FileSystem{
+		Subvolumes: []*native.Subvolume{
+			{TreeID: 5},
+			{TreeID: 257},
+		},
+	}
+
+	sv, err := findSubvolume(fs, 257)
+	if err != nil {
+		t.Fatalf("findSubvolume: %v", err)
+	}
+	if sv.TreeID != 257 {
+		t.Errorf("TreeID = %d, want 257", sv.TreeID)
+	}
+
+	if _, err := findSubvolume(fs, 999); err == nil {
+		t.Fatal("expected an error for an unknown tree id")
+	}
+}
+
+func TestDirectoryType(t *testing.T) {
+	cases := []struct {
+		mode uint32
+		want fuse.DirentType
+	}{
+		{sIFDIR, fuse.DT_Dir},
+		{sIFLNK, fuse.DT_Link},
+		{0100644, fuse.DT_File},
+	}
+	for _, c := range cases {
+		got := directoryType(native.Inode{Mode: c.mode})
+		if got != c.want {
+			t.Errorf("directoryType(mode=0%o) = %v, want %v", c.mode, got, c.want)
+		}
+	}
+}
+
+func TestNodeAttrSetsModeBits(t *testing.T) {
+	n := &node{entry: &entry{inode: native.Inode{
+		Number: 42,
+		Size:   100,
+		Mode:   sIFDIR | 0755,
+		UID:    1000,
+		GID:    1000,
+		Nlink:  2,
+		Mtime:  time.Unix(1000, 0),
+	}}}
+
+	var a fuse.Attr
+	if err := n.Attr(nil, &a); err != nil {
+		t.Fatalf("Attr: %v", err)
+	}
+	if a.Inode != 42 {
+		t.Errorf("Inode = %d, want 42", a.Inode)
+	}
+	if a.Mode&os.ModeDir == 0 {
+		t.Error("expected ModeDir to be set for a directory inode")
+	}
+}
+
+func TestNodeLookupAndReadDirAll(t *testing.T) {
+	root := &entry{path: "/", children: []string{"/file"}}
+	file := &entry{path: "/file", inode: native.Inode{Number: 7, Mode: 0100644}}
+	fsys := &fileSystem{index: map[string]*entry{
+		"/":     root,
+		"/file": file,
+	}}
+	rootNode := &node{fs: fsys, entry: root}
+
+	child, err := rootNode.Lookup(nil, "file")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if child.(*node).entry.inode.Number != 7 {
+		t.Errorf("looked up the wrong entry")
+	}
+
+	if _, err := rootNode.Lookup(nil, "missing"); err != fuse.ENOENT {
+		t.Errorf("Lookup(missing) = %v, want fuse.ENOENT", err)
+	}
+
+	dirents, err := rootNode.ReadDirAll(nil)
+	if err != nil {
+		t.Fatalf("ReadDirAll: %v", err)
+	}
+	if len(dirents) != 1 || dirents[0].Name != "file" {
+		t.Errorf("ReadDirAll = %+v, want a single \"file\" entry", dirents)
+	}
+}
@@ -0,0 +1,127 @@
+// Package native implements a pure-Go reader for on-disk btrfs structures,
+// modelled after the approach used by lukeshu's btrfs-progs-ng: a reflection
+// based binary decoder that follows the Go layout of annotated structs
+// (their `binstruct:"..."` tags document the on-disk shape), layered with
+// superblock, chunk-tree and root-tree parsers. It lets callers inspect a
+// btrfs filesystem image without shelling out to btrfs-progs and without the
+// filesystem being mounted.
+package native
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is implemented by types that know how to encode themselves to
+// the little-endian on-disk btrfs format.
+type Marshaler interface {
+	MarshalBinary() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that know how to decode themselves
+// from the little-endian on-disk btrfs format.
+type Unmarshaler interface {
+	UnmarshalBinary(data []byte) (int, error)
+}
+
+// Unmarshal decodes data into v, which must be a pointer to a struct (or a
+// type implementing Unmarshaler). Struct fields are decoded in declaration
+// order, each by its Go type: fixed-width integers by their native size,
+// arrays and nested structs element by element. `binstruct:"..."` tags are
+// not read; a fixed-length byte field is expressed as a Go array (e.g.
+// [16]byte). It returns the number of bytes consumed from data.
+func Unmarshal(data []byte, v interface{}) (int, error) {
+	if u, ok := v.(Unmarshaler); ok {
+		return u.UnmarshalBinary(data)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return 0, fmt.Errorf("binstruct: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+	return unmarshalValue(data, rv.Elem())
+}
+
+func unmarshalValue(data []byte, rv reflect.Value) (int, error) {
+	if rv.CanAddr() {
+		if u, ok := rv.Addr().Interface().(Unmarshaler); ok {
+			return u.UnmarshalBinary(data)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		off := 0
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rv.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			// A "-" tag means the field's Go value isn't meaningful (often
+			// because several on-disk fields were collapsed into one Go
+			// field elsewhere), but its on-disk bytes are still there and
+			// must still be consumed so later fields land at the right
+			// offset.
+			n, err := unmarshalValue(data[off:], field)
+			if err != nil {
+				return 0, fmt.Errorf("binstruct: field %s: %w", rt.Field(i).Name, err)
+			}
+			off += n
+		}
+		return off, nil
+	case reflect.Array:
+		off := 0
+		for i := 0; i < rv.Len(); i++ {
+			n, err := unmarshalValue(data[off:], rv.Index(i))
+			if err != nil {
+				return 0, err
+			}
+			off += n
+		}
+		return off, nil
+	case reflect.Uint8:
+		if len(data) < 1 {
+			return 0, fmt.Errorf("binstruct: unexpected EOF")
+		}
+		rv.SetUint(uint64(data[0]))
+		return 1, nil
+	case reflect.Uint16:
+		if len(data) < 2 {
+			return 0, fmt.Errorf("binstruct: unexpected EOF")
+		}
+		rv.SetUint(uint64(binary.LittleEndian.Uint16(data)))
+		return 2, nil
+	case reflect.Uint32:
+		if len(data) < 4 {
+			return 0, fmt.Errorf("binstruct: unexpected EOF")
+		}
+		rv.SetUint(uint64(binary.LittleEndian.Uint32(data)))
+		return 4, nil
+	case reflect.Uint64:
+		if len(data) < 8 {
+			return 0, fmt.Errorf("binstruct: unexpected EOF")
+		}
+		rv.SetUint(binary.LittleEndian.Uint64(data))
+		return 8, nil
+	case reflect.Int64:
+		if len(data) < 8 {
+			return 0, fmt.Errorf("binstruct: unexpected EOF")
+		}
+		rv.SetInt(int64(binary.LittleEndian.Uint64(data)))
+		return 8, nil
+	default:
+		return 0, fmt.Errorf("binstruct: unsupported kind %s", rv.Kind())
+	}
+}
+
+// Marshal encodes v, which must implement Marshaler, back into the on-disk
+// format. There is no reflection-based encoding: any other value returns an
+// error.
+func Marshal(v interface{}) ([]byte, error) {
+	if m, ok := v.(Marshaler); ok {
+		return m.MarshalBinary()
+	}
+	return nil, fmt.Errorf("binstruct: %T does not implement Marshaler", v)
+}
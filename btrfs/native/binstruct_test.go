@@ -0,0 +1,91 @@
+package native
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestUnmarshalSkipsPaddingWithoutLosingOffset(t *testing.T) {
+	var raw struct {
+		Skipped uint64 `binstruct:"-"`
+		Real    uint32
+	}
+
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint64(data[0:8], 0xdeadbeefdeadbeef)
+	binary.LittleEndian.PutUint32(data[8:12], 0x11223344)
+
+	n, err := Unmarshal(data, &raw)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if n != 12 {
+		t.Fatalf("consumed %d bytes, want 12 (the \"-\" field must still advance the offset)", n)
+	}
+	if raw.Real != 0x11223344 {
+		t.Fatalf("Real = 0x%x, want 0x11223344 (Real was decoded from the wrong offset)", raw.Real)
+	}
+}
+
+func TestDecodeInodeItem(t *testing.T) {
+	// Mirrors the layout of the anonymous struct in decodeInodeItem:
+	// Generation, TransID, Size, NBytes, BlockGroup, Nlink, UID, GID,
+	// Mode, Rdev, Flags, Sequence, Reserved[4], AtimeSec, AtimeNsec,
+	// CtimeSec, CtimeNsec, MtimeSec, MtimeNsec.
+	var buf bytes.Buffer
+	putU64 := func(v uint64) { binary.Write(&buf, binary.LittleEndian, v) }
+	putU32 := func(v uint32) { binary.Write(&buf, binary.LittleEndian, v) }
+
+	putU64(1)          // Generation (skipped)
+	putU64(2)          // TransID (skipped)
+	putU64(4096)       // Size
+	putU64(4096)       // NBytes (skipped)
+	putU64(0)          // BlockGroup (skipped)
+	putU32(1)          // Nlink
+	putU32(1000)       // UID
+	putU32(1000)       // GID
+	putU32(0100644)    // Mode
+	putU64(0)          // Rdev (skipped)
+	putU64(0)          // Flags (skipped)
+	putU64(0)          // Sequence (skipped)
+	for i := 0; i < 4; i++ {
+		putU64(0) // Reserved (skipped)
+	}
+	putU64(1000) // AtimeSec (skipped)
+	putU32(0)    // AtimeNsec (skipped)
+	putU64(2000) // CtimeSec (skipped)
+	putU32(0)    // CtimeNsec (skipped)
+	putU64(3000) // MtimeSec
+	putU32(7)    // MtimeNsec
+
+	inode, err := decodeInodeItem(42, buf.Bytes())
+	if err != nil {
+		t.Fatalf("decodeInodeItem: %v", err)
+	}
+
+	if inode.Number != 42 {
+		t.Errorf("Number = %d, want 42", inode.Number)
+	}
+	if inode.Size != 4096 {
+		t.Errorf("Size = %d, want 4096", inode.Size)
+	}
+	if inode.Nlink != 1 {
+		t.Errorf("Nlink = %d, want 1", inode.Nlink)
+	}
+	if inode.UID != 1000 {
+		t.Errorf("UID = %d, want 1000", inode.UID)
+	}
+	if inode.GID != 1000 {
+		t.Errorf("GID = %d, want 1000", inode.GID)
+	}
+	if inode.Mode != 0100644 {
+		t.Errorf("Mode = 0%o, want 0100644", inode.Mode)
+	}
+	if got, want := inode.Mtime.Unix(), int64(3000); got != want {
+		t.Errorf("Mtime.Unix() = %d, want %d", got, want)
+	}
+	if inode.Mtime.Nanosecond() != 7 {
+		t.Errorf("Mtime.Nanosecond() = %d, want 7", inode.Mtime.Nanosecond())
+	}
+}
@@ -0,0 +1,157 @@
+package native
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Stripe mirrors struct btrfs_stripe: one physical extent backing a chunk,
+// on a given device.
+type Stripe struct {
+	DevID  uint64
+	Offset uint64
+	DevUUID [16]byte `binstruct:"[0x10]byte"`
+}
+
+// Chunk mirrors struct btrfs_chunk: a mapping from a logical address range
+// to one or more physical Stripes, plus the RAID profile in Type.
+type Chunk struct {
+	Logical    uint64
+	Length     uint64
+	Owner      uint64
+	StripeLen  uint64
+	Type       uint64
+	NumStripes uint16
+	Stripes    []Stripe
+}
+
+func (c Chunk) contains(logical uint64) bool {
+	return logical >= c.Logical && logical < c.Logical+c.Length
+}
+
+// ChunkTree is the in-memory logical→physical address map built by walking
+// the CHUNK_TREE, analogous to what the kernel keeps in btrfs_fs_info's
+// mapping_tree. Mapping is the only operation most callers need.
+type ChunkTree struct {
+	chunks []Chunk
+}
+
+// Mapping resolves a logical byte address to the device ID and physical
+// offset of its first stripe. Multi-stripe (RAID) profiles beyond the
+// first mirror are not yet exposed; callers needing full redundancy
+// information can inspect Chunks() directly.
+func (t *ChunkTree) Mapping(logical uint64) (devID uint64, physical uint64, err error) {
+	idx := sort.Search(len(t.chunks), func(i int) bool {
+		return t.chunks[i].Logical+t.chunks[i].Length > logical
+	})
+	if idx == len(t.chunks) || !t.chunks[idx].contains(logical) {
+		return 0, 0, fmt.Errorf("native: no chunk maps logical address 0x%x", logical)
+	}
+	chunk := t.chunks[idx]
+	if len(chunk.Stripes) == 0 {
+		return 0, 0, fmt.Errorf("native: chunk at 0x%x has no stripes", chunk.Logical)
+	}
+	stripe := chunk.Stripes[0]
+	return stripe.DevID, stripe.Offset + (logical - chunk.Logical), nil
+}
+
+// Chunks returns every chunk known to the tree, sorted by logical address.
+func (t *ChunkTree) Chunks() []Chunk {
+	return t.chunks
+}
+
+// readSysChunkArray decodes the SYS_CHUNK_ARRAY embedded directly in the
+// superblock (bytes sb.sys_chunk_array), which bootstraps the chunk tree:
+// it contains just enough SYSTEM-type chunks to locate the chunk tree root
+// itself before any other logical→physical mapping exists.
+func readSysChunkArray(sb *Superblock, raw []byte) (*ChunkTree, error) {
+	t := &ChunkTree{}
+	off := 0
+	for off < len(raw) {
+		var key Key
+		n, err := Unmarshal(raw[off:], &key)
+		if err != nil {
+			return nil, fmt.Errorf("native: decoding sys chunk array key: %w", err)
+		}
+		off += n
+		if key.Type != TypeChunkItem {
+			return nil, fmt.Errorf("native: unexpected key type %d in sys chunk array", key.Type)
+		}
+		chunk, n, err := decodeChunk(raw[off:])
+		if err != nil {
+			return nil, fmt.Errorf("native: decoding sys chunk: %w", err)
+		}
+		chunk.Logical = key.Offset
+		off += n
+		t.chunks = append(t.chunks, chunk)
+	}
+	t.sort()
+	return t, nil
+}
+
+func decodeChunk(data []byte) (Chunk, int, error) {
+	var fixed struct {
+		Length     uint64
+		Owner      uint64
+		StripeLen  uint64
+		Type       uint64
+		IOAlign    uint32 `binstruct:"-"`
+		IOWidth    uint32 `binstruct:"-"`
+		SectorSize uint32 `binstruct:"-"`
+		NumStripes uint16
+		SubStripes uint16 `binstruct:"-"`
+	}
+	n, err := Unmarshal(data, &fixed)
+	if err != nil {
+		return Chunk{}, 0, err
+	}
+
+	c := Chunk{
+		Length:     fixed.Length,
+		Owner:      fixed.Owner,
+		StripeLen:  fixed.StripeLen,
+		Type:       fixed.Type,
+		NumStripes: fixed.NumStripes,
+	}
+	for i := uint16(0); i < c.NumStripes; i++ {
+		var s Stripe
+		sn, err := Unmarshal(data[n:], &s)
+		if err != nil {
+			return Chunk{}, 0, fmt.Errorf("stripe %d: %w", i, err)
+		}
+		n += sn
+		c.Stripes = append(c.Stripes, s)
+	}
+	return c, n, nil
+}
+
+func (t *ChunkTree) sort() {
+	sort.Slice(t.chunks, func(i, j int) bool { return t.chunks[i].Logical < t.chunks[j].Logical })
+}
+
+// walkChunkTree walks the full CHUNK_TREE starting at the superblock's
+// chunk_root, using bootstrap (built from the superblock's sys chunk array)
+// to resolve the root's own logical address, and merges in every CHUNK_ITEM
+// found along the way.
+func walkChunkTree(r io.ReaderAt, sb *Superblock, bootstrap *ChunkTree) (*ChunkTree, error) {
+	full := &ChunkTree{chunks: append([]Chunk{}, bootstrap.chunks...)}
+
+	err := walkLeaves(r, sb, bootstrap, sb.ChunkRoot, true, func(item Item, payload []byte) error {
+		if item.Key.Type != TypeChunkItem {
+			return nil
+		}
+		chunk, _, err := decodeChunk(payload)
+		if err != nil {
+			return err
+		}
+		chunk.Logical = item.Key.Offset
+		full.chunks = append(full.chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("native: walking chunk tree: %w", err)
+	}
+	full.sort()
+	return full, nil
+}
@@ -0,0 +1,31 @@
+package native
+
+import "os"
+
+// OpenPath opens the device or image at path and reads it as a btrfs
+// filesystem, closing the file if reading fails. The returned FileSystem
+// keeps the file open for on-demand reads (e.g. Subvolume.Walk); callers
+// are responsible for closing it via Close.
+func OpenPath(path string) (*FileSystem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := Open(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	fs.closer = f
+	return fs, nil
+}
+
+// Close releases the underlying file opened by OpenPath. It is a no-op for
+// FileSystems opened via Open directly with a caller-owned io.ReaderAt.
+func (fs *FileSystem) Close() error {
+	if fs.closer == nil {
+		return nil
+	}
+	return fs.closer.Close()
+}
@@ -0,0 +1,122 @@
+package native
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// SuperblockMagic is the fixed magic string every valid btrfs superblock
+// starts with, found at offset 0x40 within the superblock.
+const SuperblockMagic = "_BHRfS_M"
+
+// SuperblockOffsets lists the well-known byte offsets at which a btrfs
+// superblock (and its backup copies) may be found on a device.
+var SuperblockOffsets = []int64{0x10000, 0x4000000, 0x4000000000, 0x4000000000000}
+
+const superblockSize = 0x1000
+
+// Superblock mirrors struct btrfs_super_block from the kernel headers. Only
+// the fields needed to bootstrap the chunk and root trees are decoded; the
+// rest of the 4KiB block is left unparsed.
+type Superblock struct {
+	Checksum       [32]byte `binstruct:"[0x20]byte"`
+	FSUUID         [16]byte `binstruct:"[0x10]byte"`
+	PhysicalAddr   uint64
+	Flags          uint64
+	Magic          [8]byte `binstruct:"[0x8]byte"`
+	Generation     uint64
+	Root           uint64
+	ChunkRoot      uint64
+	LogRoot        uint64
+	LogRootTransID uint64
+	TotalBytes     uint64
+	BytesUsed      uint64
+	RootDirObjID   uint64
+	NumDevices     uint64
+	SectorSize     uint32
+	NodeSize       uint32
+	LeafSize       uint32
+	StripeSize     uint32
+	SysChunkSize   uint32
+	ChunkRootGen   uint64
+
+	raw []byte
+}
+
+// sysChunkArrayOffset mirrors the layout of struct btrfs_super_block: the
+// array itself is a fixed 0x800-byte region at offset 0x32b; how much of it
+// is actually in use is given separately by the sys_chunk_array_size field
+// at offset 0xa0, decoded into Superblock.SysChunkSize.
+const (
+	sysChunkArrayOffset  = 0x32b
+	sysChunkArrayMaxSize = 0x800
+)
+
+// sysChunkArrayBytes returns the in-use portion of the superblock's
+// embedded SYS_CHUNK_ARRAY, used to bootstrap the chunk tree.
+func sysChunkArrayBytes(sb *Superblock) []byte {
+	size := sb.SysChunkSize
+	if size > sysChunkArrayMaxSize {
+		size = sysChunkArrayMaxSize
+	}
+	return sb.raw[sysChunkArrayOffset : sysChunkArrayOffset+int(size)]
+}
+
+// ReadSuperblock reads and validates a superblock, verifying its CRC32C
+// checksum and magic number. It tries each offset in SuperblockOffsets in
+// order and returns the first valid one.
+func ReadSuperblock(r io.ReaderAt) (*Superblock, error) {
+	var lastErr error
+	for _, off := range SuperblockOffsets {
+		sb, err := readSuperblockAt(r, off)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return sb, nil
+	}
+	return nil, fmt.Errorf("native: no valid btrfs superblock found: %w", lastErr)
+}
+
+func readSuperblockAt(r io.ReaderAt, offset int64) (*Superblock, error) {
+	buf := make([]byte, superblockSize)
+	if _, err := r.ReadAt(buf, offset); err != nil {
+		return nil, fmt.Errorf("reading superblock at 0x%x: %w", offset, err)
+	}
+
+	sb := &Superblock{}
+	if _, err := Unmarshal(buf, sb); err != nil {
+		return nil, fmt.Errorf("decoding superblock at 0x%x: %w", offset, err)
+	}
+
+	if string(sb.Magic[:]) != SuperblockMagic {
+		return nil, fmt.Errorf("superblock at 0x%x: bad magic %q", offset, sb.Magic)
+	}
+
+	if err := verifyChecksum(buf); err != nil {
+		return nil, fmt.Errorf("superblock at 0x%x: %w", offset, err)
+	}
+
+	sb.raw = buf
+	return sb, nil
+}
+
+// verifyChecksum recomputes the CRC32C over bytes [0x20:0x1000) of the
+// superblock and compares it against the checksum stored at [0x0:0x20).
+// btrfs only uses the first 4 bytes of that field for the default CRC32C
+// checksum algorithm; the remainder is zero-padded.
+func verifyChecksum(buf []byte) error {
+	want := buf[0:4]
+	got := crc32.Checksum(buf[0x20:superblockSize], crc32.MakeTable(crc32.Castagnoli))
+	if want[0] != byte(got) || want[1] != byte(got>>8) || want[2] != byte(got>>16) || want[3] != byte(got>>24) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
+
+// UUID formats the filesystem UUID in the canonical 8-4-4-4-12 form.
+func (sb *Superblock) UUID() string {
+	u := sb.FSUUID
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
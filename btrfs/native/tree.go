@@ -0,0 +1,100 @@
+package native
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const headerSize = 0x65
+
+// readNode reads the node or leaf at the given logical address, resolving
+// it to a physical offset via chunkTree first. When verifyChecksums is
+// true, it recomputes the node's CRC32C and rejects a mismatch, the same
+// way ReadSuperblock does for the superblock.
+func readNode(r io.ReaderAt, sb *Superblock, chunkTree *ChunkTree, logical uint64, verifyChecksums bool) (*Header, []byte, error) {
+	_, physical, err := chunkTree.Mapping(logical)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buf := make([]byte, sb.NodeSize)
+	if _, err := r.ReadAt(buf, int64(physical)); err != nil {
+		return nil, nil, fmt.Errorf("reading node at logical 0x%x (physical 0x%x): %w", logical, physical, err)
+	}
+
+	if verifyChecksums {
+		if err := verifyNodeChecksum(buf); err != nil {
+			return nil, nil, fmt.Errorf("node at logical 0x%x: %w", logical, err)
+		}
+	}
+
+	hdr := &Header{}
+	if _, err := Unmarshal(buf, hdr); err != nil {
+		return nil, nil, fmt.Errorf("decoding header at logical 0x%x: %w", logical, err)
+	}
+	return hdr, buf, nil
+}
+
+// verifyNodeChecksum recomputes the CRC32C over buf[0x20:] (everything
+// past struct btrfs_header's checksum field) and compares it against the
+// checksum stored at buf[0x0:0x20], the same layout and default algorithm
+// ReadSuperblock verifies for the superblock.
+func verifyNodeChecksum(buf []byte) error {
+	want := buf[0:4]
+	got := crc32.Checksum(buf[0x20:], crc32.MakeTable(crc32.Castagnoli))
+	if want[0] != byte(got) || want[1] != byte(got>>8) || want[2] != byte(got>>16) || want[3] != byte(got>>24) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
+
+// walkLeaves performs a depth-first walk of the tree rooted at the given
+// logical address, calling fn once per item found in every leaf, with the
+// item's decoded key and a slice of its raw payload bytes.
+func walkLeaves(r io.ReaderAt, sb *Superblock, chunkTree *ChunkTree, root uint64, verifyChecksums bool, fn func(Item, []byte) error) error {
+	hdr, buf, err := readNode(r, sb, chunkTree, root, verifyChecksums)
+	if err != nil {
+		return err
+	}
+
+	if hdr.Level == 0 {
+		return walkLeafItems(hdr, buf, fn)
+	}
+
+	off := headerSize
+	for i := uint32(0); i < hdr.NumItems; i++ {
+		var ptr KeyPtr
+		n, err := Unmarshal(buf[off:], &ptr)
+		if err != nil {
+			return fmt.Errorf("decoding key ptr %d: %w", i, err)
+		}
+		off += n
+		if err := walkLeaves(r, sb, chunkTree, ptr.BlockNr, verifyChecksums, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkLeafItems(hdr *Header, buf []byte, fn func(Item, []byte) error) error {
+	off := headerSize
+	for i := uint32(0); i < hdr.NumItems; i++ {
+		var item Item
+		n, err := Unmarshal(buf[off:], &item)
+		if err != nil {
+			return fmt.Errorf("decoding item %d: %w", i, err)
+		}
+		off += n
+
+		start := headerSize + int(item.Offset)
+		end := start + int(item.Size)
+		if start < 0 || end > len(buf) || start > end {
+			return fmt.Errorf("item %d payload out of bounds", i)
+		}
+		if err := fn(item, buf[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
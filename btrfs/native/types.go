@@ -0,0 +1,78 @@
+package native
+
+// Well-known tree object IDs, mirroring btrfs_tree_objectid in the kernel
+// headers. Only a few are listed; the reader finds the trees it walks
+// through the superblock and ROOT_ITEMs, not through these IDs.
+const (
+	ObjRootTree  = 1
+	ObjChunkTree = 3
+	ObjDevTree   = 4
+	ObjFSTree    = 5
+)
+
+// Item types, mirroring the BTRFS_*_KEY constants. Again, only a subset;
+// of these, the package decodes INODE_ITEM, INODE_REF, FILE_EXTENT_ITEM,
+// ROOT_ITEM, DEV_ITEM and CHUNK_ITEM items.
+const (
+	TypeInodeItem      = 1
+	TypeInodeRef       = 12
+	TypeDirItem        = 84
+	TypeDirIndex       = 96
+	TypeFileExtentItem = 108
+	TypeRootItem       = 132
+	TypeRootRef        = 156
+	TypeRootBackref    = 144
+	TypeDevItem        = 216
+	TypeChunkItem      = 228
+	TypeQgroupInfo     = 242
+)
+
+// Key is the (objectid, type, offset) triple used to order and look up
+// every item in every btrfs b-tree, mirroring struct btrfs_disk_key.
+type Key struct {
+	ObjectID uint64
+	Type     uint8
+	Offset   uint64
+}
+
+// Less implements the ordering btrfs uses to keep items sorted within a
+// node: objectid, then type, then offset.
+func (k Key) Less(other Key) bool {
+	if k.ObjectID != other.ObjectID {
+		return k.ObjectID < other.ObjectID
+	}
+	if k.Type != other.Type {
+		return k.Type < other.Type
+	}
+	return k.Offset < other.Offset
+}
+
+// Header mirrors struct btrfs_header, the common prefix of every node and
+// leaf in a btrfs b-tree.
+type Header struct {
+	Checksum   [32]byte `binstruct:"[0x20]byte"`
+	FSID       [16]byte `binstruct:"[0x10]byte"`
+	ByteNr     uint64
+	Flags      uint64
+	ChunkTree  [16]byte `binstruct:"[0x10]byte"`
+	Generation uint64
+	Owner      uint64
+	NumItems   uint32
+	Level      uint8
+}
+
+// Item mirrors struct btrfs_item: a key plus the offset/size of its payload
+// within the leaf, measured from the end of the leaf header.
+type Item struct {
+	Key    Key
+	Offset uint32
+	Size   uint32
+}
+
+// KeyPtr mirrors struct btrfs_key_ptr, used in interior nodes to point at a
+// child node by its logical address.
+type KeyPtr struct {
+	Key        Key
+	BlockNr    uint64
+	Generation uint64
+}
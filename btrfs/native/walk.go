@@ -0,0 +1,184 @@
+package native
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WalkOptions constrains a Subvolume.Walk.
+type WalkOptions struct {
+	// MaxDepth limits how many path components below the subvolume root
+	// are visited. Zero (the default) means unlimited.
+	MaxDepth int
+	// Prefix, if non-empty, restricts the walk to paths starting with it.
+	Prefix string
+	// SkipChecksums disables verifying each FS-tree node's checksum while
+	// walking, so a caller can still recover what's readable from a
+	// filesystem with known node corruption instead of the walk failing
+	// outright at the first bad node.
+	SkipChecksums bool
+}
+
+// rootInode is BTRFS_FIRST_FREE_OBJECTID's well-known parent: the
+// subvolume's own root directory has no INODE_REF, since it has no parent
+// within its own FS tree.
+const rootInode = 256
+
+// Walk enumerates every INODE_ITEM in the subvolume's FS tree, calling fn
+// once per inode with its path relative to the subvolume root (the root
+// directory itself is reported as "/"). A hardlinked inode is visited once,
+// under the lexicographically first of its (parent, name) pairs; its other
+// names are not reported separately, since the metadata (size, extents,
+// ...) lives on the inode, not the name.
+func (sv *Subvolume) Walk(fn func(path string, inode Inode) error) error {
+	return sv.WalkOpts(WalkOptions{}, fn)
+}
+
+// WalkOpts is like Walk but accepts WalkOptions.
+func (sv *Subvolume) WalkOpts(opts WalkOptions, fn func(path string, inode Inode) error) error {
+	if sv.fs == nil {
+		return fmt.Errorf("native: subvolume %d was not opened via a FileSystem", sv.TreeID)
+	}
+
+	refs := map[uint64][]inodeRef{}  // child inum -> names
+	inodes := map[uint64]Inode{}     // inum -> metadata (without extents)
+	extents := map[uint64][]FileExtent{}
+
+	err := walkLeaves(sv.fs.r, sv.fs.sb, sv.fs.chunkTree, sv.ByteNr, !opts.SkipChecksums, func(item Item, payload []byte) error {
+		switch item.Key.Type {
+		case TypeInodeItem:
+			inode, err := decodeInodeItem(item.Key.ObjectID, payload)
+			if err != nil {
+				return fmt.Errorf("inode %d: %w", item.Key.ObjectID, err)
+			}
+			inodes[item.Key.ObjectID] = inode
+		case TypeInodeRef:
+			ref, err := decodeInodeRef(item.Key.ObjectID, item.Key.Offset, payload)
+			if err != nil {
+				return fmt.Errorf("inode ref %d: %w", item.Key.ObjectID, err)
+			}
+			refs[ref.child] = append(refs[ref.child], ref)
+		case TypeFileExtentItem:
+			fe, err := decodeFileExtentItem(item.Key.Offset, payload)
+			if err != nil {
+				return fmt.Errorf("file extent %d: %w", item.Key.ObjectID, err)
+			}
+			extents[item.Key.ObjectID] = append(extents[item.Key.ObjectID], fe)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("native: walking fs tree of subvolume %d: %w", sv.TreeID, err)
+	}
+
+	for inum, fes := range extents {
+		sort.Slice(fes, func(i, j int) bool { return fes[i].FileOffset < fes[j].FileOffset })
+		inode := inodes[inum]
+		inode.extents = fes
+		inodes[inum] = inode
+	}
+
+	resolver := &pathResolver{refs: refs, maxDepth: opts.MaxDepth}
+
+	for inum, inode := range inodes {
+		path, ok, err := resolver.resolve(inum)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue // beyond MaxDepth
+		}
+		if opts.Prefix != "" && !strings.HasPrefix(path, opts.Prefix) {
+			continue
+		}
+		if err := fn(path, inode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pathResolver reconstructs full paths by following INODE_REF chains
+// upward from a child inode to the subvolume root, memoizing results and
+// detecting cycles.
+type pathResolver struct {
+	refs     map[uint64][]inodeRef
+	maxDepth int
+	cache    map[uint64]pathResult
+}
+
+// pathResult is what pathResolver.cache memoizes per inode: either a
+// resolved path, or ok=false because the inode sits beyond maxDepth.
+type pathResult struct {
+	path string
+	ok   bool
+}
+
+func (r *pathResolver) resolve(inum uint64) (path string, ok bool, err error) {
+	return r.resolveAncestor(inum, nil)
+}
+
+// resolveAncestor resolves inum's path, consulting and populating r.cache
+// so that siblings sharing a long common ancestor chain only walk that
+// chain once. visiting tracks the current top-level resolve's call chain
+// to detect cycles; it is nil (and lazily allocated) on the initial call.
+func (r *pathResolver) resolveAncestor(inum uint64, visiting map[uint64]bool) (path string, ok bool, err error) {
+	if inum == rootInode {
+		return "/", true, nil
+	}
+	if cached, hit := r.cache[inum]; hit {
+		return cached.path, cached.ok, nil
+	}
+	if visiting == nil {
+		visiting = map[uint64]bool{}
+	}
+	if visiting[inum] {
+		return "", false, fmt.Errorf("native: cycle detected resolving path for inode %d", inum)
+	}
+	visiting[inum] = true
+
+	names := r.refs[inum]
+	if len(names) == 0 {
+		return "", false, fmt.Errorf("native: inode %d has no INODE_REF (orphaned or root without a parent link)", inum)
+	}
+
+	// An inode may have several names (hardlinks); report the walk under
+	// the first one found, deterministically picked by parent then name.
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].parent != names[j].parent {
+			return names[i].parent < names[j].parent
+		}
+		return names[i].name < names[j].name
+	})
+	ref := names[0]
+
+	parentPath, ok, err := r.resolveAncestor(ref.parent, visiting)
+	if err != nil {
+		return "", false, err
+	}
+	if !ok {
+		r.cacheResult(inum, pathResult{ok: false})
+		return "", false, nil
+	}
+
+	full := parentPath + "/" + ref.name
+	if parentPath == "/" {
+		full = "/" + ref.name
+	}
+
+	if r.maxDepth > 0 && strings.Count(full, "/") > r.maxDepth {
+		r.cacheResult(inum, pathResult{ok: false})
+		return "", false, nil
+	}
+
+	r.cacheResult(inum, pathResult{path: full, ok: true})
+	return full, true, nil
+}
+
+func (r *pathResolver) cacheResult(inum uint64, res pathResult) {
+	if r.cache == nil {
+		r.cache = map[uint64]pathResult{}
+	}
+	r.cache[inum] = res
+}
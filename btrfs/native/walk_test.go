@@ -0,0 +1,99 @@
+package native
+
+import "testing"
+
+func TestPathResolverResolvesNestedPath(t *testing.T) {
+	// root(256) -> "dir"(300) -> "file"(301)
+	refs := map[uint64][]inodeRef{
+		300: {{child: 300, parent: rootInode, name: "dir"}},
+		301: {{child: 301, parent: 300, name: "file"}},
+	}
+	r := &pathResolver{refs: refs}
+
+	path, ok, err := r.resolve(301)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !ok {
+		t.Fatal("resolve reported not ok")
+	}
+	if path != "/dir/file" {
+		t.Errorf("path = %q, want /dir/file", path)
+	}
+}
+
+func TestPathResolverPicksFirstNameForHardlink(t *testing.T) {
+	// inode 301 has two names; resolve must deterministically pick the
+	// lexicographically first (parent, name) pair, not just whichever it
+	// sees first.
+	refs := map[uint64][]inodeRef{
+		301: {
+			{child: 301, parent: rootInode, name: "zzz"},
+			{child: 301, parent: rootInode, name: "aaa"},
+		},
+	}
+	r := &pathResolver{refs: refs}
+
+	path, ok, err := r.resolve(301)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !ok {
+		t.Fatal("resolve reported not ok")
+	}
+	if path != "/aaa" {
+		t.Errorf("path = %q, want /aaa", path)
+	}
+}
+
+func TestPathResolverCachesAncestorChain(t *testing.T) {
+	refs := map[uint64][]inodeRef{
+		300: {{child: 300, parent: rootInode, name: "dir"}},
+		301: {{child: 301, parent: 300, name: "a"}},
+		302: {{child: 302, parent: 300, name: "b"}},
+	}
+	r := &pathResolver{refs: refs}
+
+	if _, _, err := r.resolve(301); err != nil {
+		t.Fatalf("resolve(301): %v", err)
+	}
+	if _, hit := r.cache[300]; !hit {
+		t.Error("resolving 301 should have memoized its ancestor 300 in cache")
+	}
+
+	path, ok, err := r.resolve(302)
+	if err != nil {
+		t.Fatalf("resolve(302): %v", err)
+	}
+	if !ok || path != "/dir/b" {
+		t.Errorf("resolve(302) = %q, %v, want /dir/b, true", path, ok)
+	}
+}
+
+func TestPathResolverDetectsCycle(t *testing.T) {
+	refs := map[uint64][]inodeRef{
+		301: {{child: 301, parent: 302, name: "a"}},
+		302: {{child: 302, parent: 301, name: "b"}},
+	}
+	r := &pathResolver{refs: refs}
+
+	if _, _, err := r.resolve(301); err == nil {
+		t.Fatal("expected a cycle-detection error, got nil")
+	}
+}
+
+func TestPathResolverEnforcesMaxDepth(t *testing.T) {
+	refs := map[uint64][]inodeRef{
+		300: {{child: 300, parent: rootInode, name: "dir"}},
+		301: {{child: 301, parent: 300, name: "file"}},
+	}
+	r := &pathResolver{refs: refs, maxDepth: 1}
+
+	_, ok, err := r.resolve(301)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if ok {
+		t.Fatal("expected resolve to report not ok beyond maxDepth")
+	}
+}
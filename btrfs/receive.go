@@ -0,0 +1,34 @@
+package btrfs
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Receive applies a send-stream (as produced by Subvolume.Send or `btrfs
+// send`) read from r, recreating its subvolume(s) under dstPath. Unlike
+// Send, this shells out to `btrfs receive`: a send-stream's commands
+// (subvol/snapshot create, mkfile, rename, clone, write, ...) have to be
+// replayed one ioctl at a time in order, and btrfs-progs already owns that
+// state machine, so there's little to gain from reimplementing it here.
+func Receive(r io.Reader, dstPath string) error {
+	cmd := exec.Command("btrfs", "receive", dstPath)
+	cmd.Stdin = r
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("btrfs: starting btrfs receive: %w", err)
+	}
+	errLines, err := readLines(stderr)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("btrfs receive %v failed: %v: %v", dstPath, err, errLines)
+	}
+	return nil
+}
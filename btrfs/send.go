@@ -0,0 +1,21 @@
+package btrfs
+
+// SendOptions configures Subvolume.Send.
+type SendOptions struct {
+	// ParentUUID, if set, requests an incremental send relative to the
+	// snapshot with that UUID (matching Subvolume.ParentUUID), so only the
+	// changes since that snapshot are streamed.
+	//
+	// Not yet implemented: both Send backends reject a non-empty
+	// ParentUUID rather than silently falling back to a full send.
+	ParentUUID string
+	// CloneSources lists additional snapshot UUIDs the kernel may use as
+	// clone sources when building the stream, beyond ParentUUID.
+	//
+	// Not yet implemented: both Send backends reject a non-empty
+	// CloneSources rather than silently falling back to a full send.
+	CloneSources []string
+	// NoData omits file data from the stream, useful for dry runs that
+	// only need the metadata/structure of the send.
+	NoData bool
+}
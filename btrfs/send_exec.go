@@ -0,0 +1,53 @@
+//go:build btrfs_send_exec
+
+package btrfs
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Send shells out to `btrfs send` and streams its stdout to w. It's built
+// only when the btrfs_send_exec build tag is set, for environments where
+// calling BTRFS_IOC_SEND directly isn't desirable (e.g. sandboxes that
+// restrict raw ioctls but still expose btrfs-progs).
+//
+// `btrfs send -p`/`-c` expect subvolume paths, not UUIDs, and this package
+// does not yet resolve opts.ParentUUID/opts.CloneSources to the paths of
+// the snapshots they name. Until that lookup exists, Send rejects those
+// options rather than running a full send when an incremental or clone
+// send was requested.
+func (sv *Subvolume) Send(w io.Writer, opts SendOptions) error {
+	if sv.Path == "" {
+		return fmt.Errorf("btrfs: Send requires a Subvolume with a mounted Path")
+	}
+	if opts.ParentUUID != "" || len(opts.CloneSources) > 0 {
+		return fmt.Errorf("btrfs: Send: ParentUUID/CloneSources are not yet supported (requires UUID to path resolution)")
+	}
+
+	args := []string{"send"}
+	if opts.NoData {
+		args = append(args, "--no-data")
+	}
+	args = append(args, sv.Path)
+
+	cmd := exec.Command("btrfs", args...)
+	cmd.Stdout = w
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("btrfs: starting btrfs send: %w", err)
+	}
+	errLines, err := readLines(stderr)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("btrfs send %v failed: %v: %v", sv.Path, err, errLines)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package btrfs
 import (
 	"bufio"
 	"fmt"
+	"github.com/control-center/go-fsutils/btrfs/native"
 	"github.com/dustin/go-humanize"
 	"io"
 	"os/exec"
@@ -20,6 +21,21 @@ type FileSystem struct {
 	devices      []Device
 	subvolumes   []Subvolume
 	dfData       []DFData
+
+	// native is set only for FileSystems returned by NativeBackend; it
+	// owns the open file backing this FileSystem's Subvolumes, so Close
+	// must be called once the FileSystem (and any Subvolume.Walk calls
+	// on it) are no longer needed.
+	native *native.FileSystem
+}
+
+// Close releases resources held by a FileSystem obtained via NativeBackend.
+// It is a no-op for FileSystems obtained via ExecBackend.
+func (fs *FileSystem) Close() error {
+	if fs.native == nil {
+		return nil
+	}
+	return fs.native.Close()
 }
 
 func readLines(reader io.Reader) ([]string, error) {
@@ -34,7 +50,18 @@ func readLines(reader io.Reader) ([]string, error) {
 	return lines, nil
 }
 
+// GetFileSystem reads filesystem, device and usage info for the btrfs
+// filesystem at path using DefaultBackend. Use a specific Backend's
+// FileSystem method directly (e.g. NativeBackend) to bypass btrfs-progs.
+// Callers should call the returned FileSystem's Close method once done
+// with it (and with any Subvolume.Walk calls against it).
 func GetFileSystem(path string) (*FileSystem, error) {
+	return DefaultBackend.FileSystem(path)
+}
+
+// execFileSystem is the original exec.Command("btrfs", ...)-based
+// implementation, kept as ExecBackend.
+func execFileSystem(path string) (*FileSystem, error) {
 	if strings.TrimSpace(path) == "" {
 		return nil, fmt.Errorf("path cannot be empty")
 	}
@@ -139,7 +166,7 @@ func parseSubvolumes(path string, lines []string) ([]Subvolume, error) {
 		if err != nil {
 			return []Subvolume{}, err
 		}
-		sv = append(sv, subvolume)
+		sv = append(sv, *subvolume)
 	}
 	return sv, nil
 }
@@ -181,7 +208,7 @@ func readSubvolume(rootPath, subvolumePath string) (*Subvolume, error) {
 		Flags: 			-
 		Snapshot(s):
 	 */
-	sv := Subvolume{}
+	sv := Subvolume{Path: svPath}
 	for lineNum, line := range svLines {
 		if lineNum == 0 {
 			continue
@@ -189,6 +216,7 @@ func readSubvolume(rootPath, subvolumePath string) (*Subvolume, error) {
 		line = strings.TrimSpace(line)
 		fields := strings.Fields(line)
 
+		var parsed uint64
 		switch fields[0] {
 		case "Name:":
 			sv.Name = fields[1]
@@ -197,22 +225,26 @@ func readSubvolume(rootPath, subvolumePath string) (*Subvolume, error) {
 		case "Parent":
 			sv.ParentUUID = fields[2]
 		case "Creation":
-			sv.CreationTime , err = time.Parse("2005-01-2 03:04:05", fields[2])
-			if err != nil {return fmt.Errorf("error parsing timestatmp: %v: %v", line, err)}
+			sv.CreationTime, err = time.Parse("2006-01-02 15:04:05", strings.Join(fields[2:], " "))
+			if err != nil {return nil, fmt.Errorf("error parsing timestatmp: %v: %v", line, err)}
 		case "Object":
 			sv.ID= fields[2]
 		case "Generation":
-			sv.Gen, err = strconv.ParseUint(fields[2], 0, 32)
-			if err != nil {return fmt.Errorf("error parsing timestatmp: %v: %v", line, err)}
+			parsed, err = strconv.ParseUint(fields[2], 0, 32)
+			if err != nil {return nil, fmt.Errorf("error parsing Generation: %v: %v", line, err)}
+			sv.Gen = uint32(parsed)
 		case "Gen":
-			sv.GenAtCreation, err  = strconv.ParseUint(fields[3], 0, 32)
-			if err != nil {return fmt.Errorf("error parsing Generation: %v: %v", line, err)}
+			parsed, err = strconv.ParseUint(fields[3], 0, 32)
+			if err != nil {return nil, fmt.Errorf("error parsing Generation: %v: %v", line, err)}
+			sv.GenAtCreation = uint32(parsed)
 		case "Parent:":
-			sv.Parent, err  = strconv.ParseUint(fields[1], 0, 32)
-			if err != nil {return fmt.Errorf("error parsing Parent: %v: %v", line, err)}
+			parsed, err = strconv.ParseUint(fields[1], 0, 32)
+			if err != nil {return nil, fmt.Errorf("error parsing Parent: %v: %v", line, err)}
+			sv.Parent = uint32(parsed)
 		case "Top":
-			sv.TopLevel, err  = strconv.ParseUint(fields[2], 0, 32)
-			if err != nil {return fmt.Errorf("error parsing Top: %v: %v", line, err)}
+			parsed, err = strconv.ParseUint(fields[2], 0, 32)
+			if err != nil {return nil, fmt.Errorf("error parsing Top: %v: %v", line, err)}
+			sv.TopLevel = uint32(parsed)
 
 		case "Flags:":
 			continue
@@ -220,7 +252,7 @@ func readSubvolume(rootPath, subvolumePath string) (*Subvolume, error) {
 			continue
 		}
 	}
-	return sv, nil
+	return &sv, nil
 }
 
 
@@ -249,7 +281,7 @@ func parseDF(lines []string) ([]DFData, error) {
 			total := fields[2]
 			var totalBytes, usedBytes uint64
 			if strings.HasPrefix(total, "total=") {
-				total = strings.SplitAfter(total, "=")
+				total = strings.TrimSuffix(strings.TrimPrefix(total, "total="), ",")
 				if totalBytes, err = parseSize(total); err != nil {
 					return []DFData{}, err
 				}
@@ -258,7 +290,7 @@ func parseDF(lines []string) ([]DFData, error) {
 			}
 			used := fields[3]
 			if strings.HasPrefix(used, "used=") {
-				used = strings.SplitAfter(used, "=")
+				used = strings.TrimSuffix(strings.TrimPrefix(used, "used="), ",")
 				if usedBytes, err = parseSize(used); err != nil {
 					return []DFData{}, err
 				}
@@ -266,7 +298,8 @@ func parseDF(lines []string) ([]DFData, error) {
 				return []DFData{}, fmt.Errorf("expected used field: %v", line)
 			}
 
-			df = append(df, DFData{{DataType:fields[0], Level:[1], Total:totalBytes, Used: usedBytes}})
+			level := strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(fields[1], ":"), ","))
+			df = append(df, DFData{DataType: strings.TrimSuffix(fields[0], ","), Level: level, Total: totalBytes, Used: usedBytes})
 		default:
 			return []DFData{}, fmt.Errorf("Unknown fields: %v", line)
 		}
@@ -483,4 +516,19 @@ type Subvolume struct {
 	Path          string
 	//Flags  TODO:
 	//Snapshots TODO:
+
+	// native is set only for Subvolumes returned by NativeBackend; it
+	// backs Walk. Subvolumes from ExecBackend have no native reader to
+	// walk, since they were never parsed from the on-disk format.
+	native *native.Subvolume
+}
+
+// Walk enumerates every file and directory in the subvolume, calling fn
+// once per inode with its path relative to the subvolume root. It is only
+// available on Subvolumes obtained via NativeBackend.
+func (sv *Subvolume) Walk(fn func(path string, inode native.Inode) error) error {
+	if sv.native == nil {
+		return fmt.Errorf("btrfs: Walk requires a Subvolume from NativeBackend")
+	}
+	return sv.native.Walk(fn)
 }
@@ -0,0 +1,93 @@
+//go:build darwin || freebsd || dragonfly
+
+package diskinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// NewDiskInfo statfs(2)s path and returns the resulting DiskInfo.
+func NewDiskInfo(path string) (*DiskInfo, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return nil, fmt.Errorf("diskinfo: statfs %v: %w", path, err)
+	}
+
+	bsize := uint64(stat.Bsize)
+	total := uint64(stat.Blocks) * bsize
+	free := uint64(stat.Bfree) * bsize
+
+	return &DiskInfo{
+		Path:       path,
+		total:      total,
+		free:       free,
+		used:       total - free,
+		files:      stat.Files,
+		filesFree:  uint64(stat.Ffree),
+		fstypeName: fstypeName(stat),
+	}, nil
+}
+
+// Partitions enumerates every mounted filesystem via getfsstat(2). When all
+// is false, devfs mounts are skipped; no other filesystem type is filtered.
+func Partitions(all bool) ([]PartitionStat, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("diskinfo: getfsstat: %w", err)
+	}
+
+	buf := make([]unix.Statfs_t, n)
+	if _, err := unix.Getfsstat(buf, unix.MNT_NOWAIT); err != nil {
+		return nil, fmt.Errorf("diskinfo: getfsstat: %w", err)
+	}
+
+	var parts []PartitionStat
+	for _, stat := range buf {
+		fstype := fstypeName(stat)
+		if !all && fstype == "devfs" {
+			continue
+		}
+		parts = append(parts, PartitionStat{
+			Device:     bytesToString(stat.Mntfromname[:]),
+			Mountpoint: bytesToString(stat.Mntonname[:]),
+			Fstype:     fstype,
+			Opts:       mountFlagsToOpts(int64(stat.Flags)),
+		})
+	}
+	return parts, nil
+}
+
+// IOCounters is not yet implemented for this platform: per-device IO
+// statistics require IOKit (Darwin) or sysctl(3) device-specific MIBs
+// (*BSD), neither of which x/sys/unix exposes. Callers on these platforms
+// should rely on Partitions and DiskInfo.
+func IOCounters(names ...string) (map[string]IOCountersStat, error) {
+	return nil, fmt.Errorf("diskinfo: IOCounters not implemented on this platform")
+}
+
+func bytesToString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+func mountFlagsToOpts(flags int64) []string {
+	var opts []string
+	if flags&unix.MNT_RDONLY != 0 {
+		opts = append(opts, "ro")
+	} else {
+		opts = append(opts, "rw")
+	}
+	if flags&unix.MNT_NOEXEC != 0 {
+		opts = append(opts, "noexec")
+	}
+	if flags&unix.MNT_NOSUID != 0 {
+		opts = append(opts, "nosuid")
+	}
+	return opts
+}
+
+func fstypeName(stat unix.Statfs_t) string {
+	return bytesToString(stat.Fstypename[:])
+}
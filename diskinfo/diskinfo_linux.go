@@ -0,0 +1,145 @@
+//go:build linux
+
+package diskinfo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// NewDiskInfo statfs(2)s path and returns the resulting DiskInfo.
+func NewDiskInfo(path string) (*DiskInfo, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return nil, fmt.Errorf("diskinfo: statfs %v: %w", path, err)
+	}
+
+	bsize := uint64(stat.Bsize)
+	total := stat.Blocks * bsize
+	free := stat.Bfree * bsize
+
+	return &DiskInfo{
+		Path:      path,
+		Type:      int64(stat.Type),
+		total:     total,
+		free:      free,
+		used:      total - free,
+		files:     stat.Files,
+		filesFree: stat.Ffree,
+	}, nil
+}
+
+// Partitions enumerates every mounted filesystem by parsing
+// /proc/self/mountinfo. When all is false, pseudo/virtual filesystems
+// (proc, sysfs, cgroup, tmpfs, devtmpfs, and similar) are skipped, mirroring
+// gopsutil's default.
+func Partitions(all bool) ([]PartitionStat, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, fmt.Errorf("diskinfo: %w", err)
+	}
+	defer f.Close()
+
+	var parts []PartitionStat
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		sepIdx := -1
+		for i, field := range fields {
+			if field == "-" {
+				sepIdx = i
+				break
+			}
+		}
+		if sepIdx == -1 || sepIdx+3 >= len(fields) {
+			continue
+		}
+
+		mountpoint := fields[4]
+		mountOpts := fields[5]
+		fstype := fields[sepIdx+1]
+		device := fields[sepIdx+2]
+
+		if !all && isPseudoFS(fstype) {
+			continue
+		}
+
+		parts = append(parts, PartitionStat{
+			Device:     device,
+			Mountpoint: mountpoint,
+			Fstype:     fstype,
+			Opts:       strings.Split(mountOpts, ","),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("diskinfo: reading mountinfo: %w", err)
+	}
+	return parts, nil
+}
+
+var pseudoFSTypes = map[string]bool{
+	"proc": true, "sysfs": true, "cgroup": true, "cgroup2": true,
+	"tmpfs": true, "devtmpfs": true, "devpts": true, "securityfs": true,
+	"pstore": true, "debugfs": true, "tracefs": true, "mqueue": true,
+	"hugetlbfs": true, "configfs": true, "fusectl": true, "bpf": true,
+	"autofs": true, "binfmt_misc": true,
+}
+
+func isPseudoFS(fstype string) bool {
+	return pseudoFSTypes[fstype]
+}
+
+// IOCounters reads /proc/diskstats and returns cumulative IO counters keyed
+// by device name. When names is non-empty, only those devices are
+// returned.
+func IOCounters(names ...string) (map[string]IOCountersStat, error) {
+	f, err := os.Open("/proc/diskstats")
+	if err != nil {
+		return nil, fmt.Errorf("diskinfo: %w", err)
+	}
+	defer f.Close()
+
+	want := make(map[string]bool, len(names))
+	for _, n := range names {
+		want[n] = true
+	}
+
+	result := map[string]IOCountersStat{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 {
+			continue
+		}
+		name := fields[2]
+		if len(want) > 0 && !want[name] {
+			continue
+		}
+
+		stat := IOCountersStat{Name: name}
+		stat.ReadCount, _ = strconv.ParseUint(fields[3], 10, 64)
+		sectorsRead, _ := strconv.ParseUint(fields[5], 10, 64)
+		stat.ReadBytes = sectorsRead * 512
+		readTime, _ := strconv.ParseUint(fields[6], 10, 64)
+		stat.ReadTime = readTime
+
+		stat.WriteCount, _ = strconv.ParseUint(fields[7], 10, 64)
+		sectorsWritten, _ := strconv.ParseUint(fields[9], 10, 64)
+		stat.WriteBytes = sectorsWritten * 512
+		writeTime, _ := strconv.ParseUint(fields[10], 10, 64)
+		stat.WriteTime = writeTime
+
+		stat.IopsInProgress, _ = strconv.ParseUint(fields[11], 10, 64)
+
+		result[name] = stat
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("diskinfo: reading diskstats: %w", err)
+	}
+	return result, nil
+}
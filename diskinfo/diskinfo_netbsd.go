@@ -0,0 +1,45 @@
+//go:build netbsd
+
+package diskinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// NewDiskInfo statvfs(2)s path and returns the resulting DiskInfo. NetBSD
+// dropped the BSD-style statfs(2) struct in favor of statvfs(2), unlike the
+// other BSDs this package supports.
+func NewDiskInfo(path string) (*DiskInfo, error) {
+	var stat unix.Statvfs_t
+	if err := unix.Statvfs(path, &stat); err != nil {
+		return nil, fmt.Errorf("diskinfo: statvfs %v: %w", path, err)
+	}
+
+	total := stat.Blocks * stat.Bsize
+	free := stat.Bfree * stat.Bsize
+
+	return &DiskInfo{
+		Path:       path,
+		total:      total,
+		free:       free,
+		used:       total - free,
+		files:      stat.Files,
+		filesFree:  stat.Ffree,
+		fstypeName: strings.TrimRight(string(stat.Fstypename[:]), "\x00"),
+	}, nil
+}
+
+// Partitions is not yet implemented for this platform: x/sys/unix doesn't
+// expose a getvfsstat(2) wrapper for NetBSD, unlike its getfsstat(2)
+// bindings for the other BSDs this package supports.
+func Partitions(all bool) ([]PartitionStat, error) {
+	return nil, fmt.Errorf("diskinfo: Partitions not implemented on this platform")
+}
+
+// IOCounters is not yet implemented for this platform.
+func IOCounters(names ...string) (map[string]IOCountersStat, error) {
+	return nil, fmt.Errorf("diskinfo: IOCounters not implemented on this platform")
+}
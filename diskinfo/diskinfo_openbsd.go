@@ -0,0 +1,89 @@
+//go:build openbsd
+
+package diskinfo
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// NewDiskInfo statfs(2)s path and returns the resulting DiskInfo.
+func NewDiskInfo(path string) (*DiskInfo, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return nil, fmt.Errorf("diskinfo: statfs %v: %w", path, err)
+	}
+
+	bsize := uint64(stat.F_bsize)
+	total := stat.F_blocks * bsize
+	free := stat.F_bfree * bsize
+
+	return &DiskInfo{
+		Path:       path,
+		total:      total,
+		free:       free,
+		used:       total - free,
+		files:      stat.F_files,
+		filesFree:  stat.F_ffree,
+		fstypeName: openbsdBytesToString(stat.F_fstypename[:]),
+	}, nil
+}
+
+// Partitions enumerates every mounted filesystem via getfsstat(2). When all
+// is false, devfs mounts are skipped; no other filesystem type is filtered.
+func Partitions(all bool) ([]PartitionStat, error) {
+	n, err := unix.Getfsstat(nil, unix.MNT_NOWAIT)
+	if err != nil {
+		return nil, fmt.Errorf("diskinfo: getfsstat: %w", err)
+	}
+
+	buf := make([]unix.Statfs_t, n)
+	if _, err := unix.Getfsstat(buf, unix.MNT_NOWAIT); err != nil {
+		return nil, fmt.Errorf("diskinfo: getfsstat: %w", err)
+	}
+
+	var parts []PartitionStat
+	for _, stat := range buf {
+		fstype := openbsdBytesToString(stat.F_fstypename[:])
+		if !all && fstype == "devfs" {
+			continue
+		}
+		parts = append(parts, PartitionStat{
+			Device:     openbsdBytesToString(stat.F_mntfromname[:]),
+			Mountpoint: openbsdBytesToString(stat.F_mntonname[:]),
+			Fstype:     fstype,
+			Opts:       mountFlagsToOpts(int64(stat.F_flags)),
+		})
+	}
+	return parts, nil
+}
+
+// IOCounters is not yet implemented for this platform: per-device IO
+// statistics require sysctl(3) device-specific MIBs, which x/sys/unix
+// doesn't expose. Callers on this platform should rely on Partitions and
+// DiskInfo.
+func IOCounters(names ...string) (map[string]IOCountersStat, error) {
+	return nil, fmt.Errorf("diskinfo: IOCounters not implemented on this platform")
+}
+
+func openbsdBytesToString(b []byte) string {
+	return strings.TrimRight(string(b), "\x00")
+}
+
+func mountFlagsToOpts(flags int64) []string {
+	var opts []string
+	if flags&unix.MNT_RDONLY != 0 {
+		opts = append(opts, "ro")
+	} else {
+		opts = append(opts, "rw")
+	}
+	if flags&unix.MNT_NOEXEC != 0 {
+		opts = append(opts, "noexec")
+	}
+	if flags&unix.MNT_NOSUID != 0 {
+		opts = append(opts, "nosuid")
+	}
+	return opts
+}
@@ -0,0 +1,283 @@
+package diskinfo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// DefaultUsageConcurrency bounds how many paths Usage scans at once when
+// UsageOptions.Concurrency is unset. cadvisor uses the same kind of global
+// cap around its own du/find invocations, to keep a directory-usage sweep
+// from turning into an IO storm.
+const DefaultUsageConcurrency = 20
+
+// UsageOptions configures Usage.
+type UsageOptions struct {
+	// FollowSymlinks makes the walk follow symlinks instead of measuring
+	// the link itself. A followed symlink to a directory is not descended
+	// into.
+	FollowSymlinks bool
+	// IncludeXattrs adds extended attribute value sizes to a file's
+	// apparent size.
+	IncludeXattrs bool
+	// Concurrency bounds how many of the requested paths are scanned at
+	// once. Zero means DefaultUsageConcurrency.
+	Concurrency int
+	// Context, if set, lets a caller abort a long scan. Usage checks it
+	// between directory entries, not between individual bytes.
+	Context context.Context
+}
+
+// UsageResult reports apparent-size and on-disk-size usage for one path.
+type UsageResult struct {
+	ApparentBytes uint64
+	DiskBytes     uint64
+	// Method records how the number was obtained: "walked" (full
+	// directory traversal), "qgroup" (read from btrfs qgroup accounting,
+	// O(1) regardless of file count), or "statfs" (the path is itself a
+	// mountpoint and qgroup data wasn't available, so Total/Free from
+	// DiskInfo was used instead of walking).
+	Method string
+}
+
+// Usage computes ApparentBytes/DiskBytes for each of paths, walking the
+// directory tree unless it can short-circuit: when path is itself a btrfs
+// subvolume root with qgroup accounting enabled, it reads the total from
+// `btrfs qgroup show`; otherwise, when path is a filesystem mountpoint, it
+// falls back to statfs(2). Both are O(1) versus the O(files) of a walk.
+func Usage(paths []string, opts UsageOptions) (map[string]UsageResult, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultUsageConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	results := make(map[string]UsageResult, len(paths))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, p := range paths {
+		p := p
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			u, err := usageOnePath(ctx, p, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[p] = err
+				return
+			}
+			results[p] = u
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		var first error
+		for p, err := range errs {
+			if first == nil {
+				first = fmt.Errorf("diskinfo: usage of %v: %w", p, err)
+			}
+		}
+		return results, first
+	}
+	return results, nil
+}
+
+func usageOnePath(ctx context.Context, path string, opts UsageOptions) (UsageResult, error) {
+	if u, ok := qgroupUsage(path); ok {
+		return u, nil
+	}
+	if u, ok := statfsUsage(path); ok {
+		return u, nil
+	}
+	return walkUsage(ctx, path, opts)
+}
+
+// qgroupUsage tries `btrfs qgroup show --raw` against path; it only
+// succeeds when path is itself a btrfs subvolume root with qgroups
+// enabled, in which case the "0/<subvolid>" row's rfer is exactly path's
+// usage. For any other path, a subvolume's qgroup accounts for the whole
+// subvolume, not a subdirectory of it, so this falls through instead of
+// returning a grossly inflated number.
+func qgroupUsage(path string) (UsageResult, bool) {
+	if !isSubvolumeRoot(path) {
+		return UsageResult{}, false
+	}
+
+	subvolID, ok := subvolumeID(path)
+	if !ok {
+		return UsageResult{}, false
+	}
+
+	cmd := exec.Command("btrfs", "qgroup", "show", "--raw", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return UsageResult{}, false
+	}
+
+	// Output format:
+	//   qgroupid         rfer         excl
+	//   --------         ----         ----
+	//   0/257        12345678     12345678
+	//
+	// The filesystem may report many rows here (every subvolume's own
+	// qgroup, plus any higher-level ones it belongs to); only the
+	// "0/<subvolid>" row for path's own subvolume is its usage.
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 3 {
+		return UsageResult{}, false
+	}
+
+	wantQgroupID := "0/" + subvolID
+	for _, line := range lines[2:] {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[0] != wantQgroupID {
+			continue
+		}
+		rfer, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return UsageResult{}, false
+		}
+		return UsageResult{ApparentBytes: rfer, DiskBytes: rfer, Method: "qgroup"}, true
+	}
+	return UsageResult{}, false
+}
+
+// subvolumeID returns the btrfs subvolume ID that path belongs to, used to
+// pick path's own row out of `btrfs qgroup show`'s filesystem-wide output.
+func subvolumeID(path string) (string, bool) {
+	cmd := exec.Command("btrfs", "inspect-internal", "rootid", path)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", false
+	}
+	id := strings.TrimSpace(string(out))
+	if id == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// isSubvolumeRoot reports whether path is itself the root of a btrfs
+// subvolume, rather than an ordinary subdirectory within one: its qgroup
+// (if any) accounts for path's own usage rather than some containing
+// subvolume's. It compares rootid(path) against rootid(path/..): a
+// subdirectory shares its parent's subvolume id, while a subvolume root
+// (or the filesystem root) does not.
+func isSubvolumeRoot(path string) bool {
+	id, ok := subvolumeID(path)
+	if !ok {
+		return false
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return true
+	}
+	parentID, ok := subvolumeID(parent)
+	if !ok {
+		// The parent isn't on the same btrfs filesystem (or doesn't
+		// exist): path can only be the root of its own subvolume.
+		return true
+	}
+	return id != parentID
+}
+
+// statfsUsage falls back to statfs(2) Total/Free (via DiskInfo) when path
+// is itself a filesystem mountpoint and qgroupUsage couldn't answer, e.g.
+// because qgroups aren't enabled. This is still O(1) versus walking, at
+// the cost of reporting the whole filesystem's usage rather than path's.
+func statfsUsage(path string) (UsageResult, bool) {
+	if !isMountpoint(path) {
+		return UsageResult{}, false
+	}
+	di, err := NewDiskInfo(path)
+	if err != nil {
+		return UsageResult{}, false
+	}
+	return UsageResult{ApparentBytes: di.Used(), DiskBytes: di.Used(), Method: "statfs"}, true
+}
+
+// isMountpoint reports whether path's device differs from its parent
+// directory's, the standard way of detecting a mountpoint without
+// parsing /proc/mounts.
+func isMountpoint(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return true
+	}
+	parentInfo, err := os.Stat(parent)
+	if err != nil {
+		return true
+	}
+	parentStat, ok := parentInfo.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return stat.Dev != parentStat.Dev
+}
+
+func walkUsage(ctx context.Context, root string, opts UsageOptions) (UsageResult, error) {
+	var u UsageResult
+	u.Method = "walked"
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 && opts.FollowSymlinks {
+			resolved, err := os.Stat(p)
+			if err != nil {
+				return nil // broken symlink: skip rather than fail the whole walk
+			}
+			info = resolved
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		apparent := uint64(info.Size())
+		u.ApparentBytes += apparent
+		u.DiskBytes += diskUsage(info)
+
+		if opts.IncludeXattrs {
+			u.ApparentBytes += xattrSize(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return UsageResult{}, fmt.Errorf("walking %v: %w", root, err)
+	}
+	return u, nil
+}
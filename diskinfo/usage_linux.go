@@ -0,0 +1,62 @@
+//go:build linux
+
+package diskinfo
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// diskUsage returns the on-disk size of a regular file, i.e. the space its
+// blocks actually occupy, which can differ from its apparent size for
+// sparse files or compressed btrfs extents.
+func diskUsage(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return uint64(info.Size())
+	}
+	return uint64(stat.Blocks) * 512
+}
+
+// xattrSize sums the size of every extended attribute value set on path.
+func xattrSize(path string) uint64 {
+	names, err := listXattrNames(path)
+	if err != nil {
+		return 0
+	}
+
+	var total uint64
+	for _, name := range names {
+		n, err := unix.Getxattr(path, name, nil)
+		if err != nil || n <= 0 {
+			continue
+		}
+		total += uint64(n)
+	}
+	return total
+}
+
+func listXattrNames(path string) ([]string, error) {
+	size, err := unix.Listxattr(path, nil)
+	if err != nil || size <= 0 {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	n, err := unix.Listxattr(path, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	start := 0
+	for i, b := range buf[:n] {
+		if b == 0 {
+			names = append(names, string(buf[start:i]))
+			start = i + 1
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package diskinfo
+
+import "os"
+
+// diskUsage falls back to apparent size on platforms where this package
+// doesn't yet decode the platform-specific stat struct for block counts.
+func diskUsage(info os.FileInfo) uint64 {
+	return uint64(info.Size())
+}
+
+// xattrSize is not yet implemented outside Linux.
+func xattrSize(path string) uint64 {
+	return 0
+}
@@ -0,0 +1,51 @@
+package diskinfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkUsageSumsFileSizes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b"), []byte("world!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := walkUsage(context.Background(), dir, UsageOptions{})
+	if err != nil {
+		t.Fatalf("walkUsage: %v", err)
+	}
+	if u.Method != "walked" {
+		t.Errorf("Method = %q, want %q", u.Method, "walked")
+	}
+	if want := uint64(len("hello") + len("world!")); u.ApparentBytes != want {
+		t.Errorf("ApparentBytes = %d, want %d", u.ApparentBytes, want)
+	}
+}
+
+func TestUsageReturnsResultPerPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Usage([]string{dir}, UsageOptions{})
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	u, ok := results[dir]
+	if !ok {
+		t.Fatalf("no result for %v", dir)
+	}
+	if u.ApparentBytes != uint64(len("hello")) {
+		t.Errorf("ApparentBytes = %d, want %d", u.ApparentBytes, len("hello"))
+	}
+}